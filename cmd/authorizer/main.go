@@ -3,27 +3,108 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/casbin/casbin/v2/persist"
 
-	"tarot-backend/internal/auth" // 우리가 작성한 internal/auth 패키지를 임포트합니다.
+	"tarot-backend/internal/auth"    // 우리가 작성한 internal/auth 패키지를 임포트합니다.
+	"tarot-backend/internal/authz"   // RBAC/ABAC 인가 계층
+	"tarot-backend/internal/logging" // 구조화 로깅 + correlation ID
 )
 
+var (
+	// enforcer는 초기화에 성공하면 재사용되는 Casbin Enforcer입니다.
+	enforcer   *authz.Enforcer
+	enforcerMu sync.Mutex
+
+	// policyAdapter는 정책을 어디서 읽어올지 고릅니다. "s3"(기본값) 또는 "dynamodb".
+	policyAdapter = envOrDefault("AUTHZ_ADAPTER", "s3")
+
+	// 정책 CSV가 저장된 S3 버킷/키. 운영자가 파일만 교체하면 다음 cold start(또는
+	// Enforcer.ReloadPolicy 호출) 시점부터 새 정책이 반영됩니다.
+	policyBucket = os.Getenv("AUTHZ_POLICY_BUCKET")
+	policyKey    = os.Getenv("AUTHZ_POLICY_KEY")
+
+	// 정책 행이 저장된 DynamoDB 테이블. AUTHZ_ADAPTER=dynamodb일 때만 사용합니다.
+	policyTableName = os.Getenv("AUTHZ_POLICY_TABLE_NAME")
+)
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getEnforcer는 authz.Enforcer를 초기화해 재사용합니다. AWS 설정 로딩처럼 실패할 수 있는
+// 작업이므로 sync.Once로 한 번만 시도하지 않고, 아직 초기화에 성공하지 못한 동안은 호출될
+// 때마다 다시 시도합니다 — 그렇지 않으면 콜드 스타트 때의 일시적인 오류가 해당 컨테이너의
+// 남은 수명 동안 모든 요청을 영구히 Deny시키게 됩니다.
+func getEnforcer(ctx context.Context) (*authz.Enforcer, error) {
+	enforcerMu.Lock()
+	defer enforcerMu.Unlock()
+
+	if enforcer != nil {
+		return enforcer, nil
+	}
+
+	cfg, err := config.LoadDefaultAWSConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("AWS 설정 로딩 실패: %w", err)
+	}
+
+	adapter, err := newPolicyAdapter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := authz.NewEnforcer("internal/authz/model.conf", adapter)
+	if err != nil {
+		return nil, err
+	}
+
+	enforcer = e
+	return enforcer, nil
+}
+
+// newPolicyAdapter는 AUTHZ_ADAPTER 환경 변수에 따라 정책을 S3 오브젝트 또는 DynamoDB
+// 테이블에서 읽어오는 persist.Adapter를 고릅니다.
+func newPolicyAdapter(cfg aws.Config) (persist.Adapter, error) {
+	switch policyAdapter {
+	case "dynamodb":
+		return authz.NewDynamoDBAdapter(dynamodb.NewFromConfig(cfg), policyTableName), nil
+	case "s3":
+		return authz.NewS3Adapter(s3.NewFromConfig(cfg), policyBucket, policyKey), nil
+	default:
+		return nil, fmt.Errorf("알 수 없는 AUTHZ_ADAPTER 값: %s", policyAdapter)
+	}
+}
+
 // handler 함수는 AWS Lambda에 의해 직접 호출되는 메인 로직입니다.
 // API Gateway Custom Authorizer(REQUEST 타입) 이벤트가 발생할 때마다 실행됩니다.
 func handler(ctx context.Context, event events.APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
-	// 어떤 요청이 들어왔는지 로그를 남깁니다. (디버깅에 유용)
-	log.Printf("Authorizer 이벤트 수신: %+v", event)
+	start := time.Now()
+	corrID := logging.CorrelationID(event.Headers)
+	logger := logging.New().With("correlation_id", corrID, "method_arn", event.MethodArn)
+
+	// 이벤트를 그대로 %+v로 찍으면 Authorization 헤더/토큰이 원문 그대로 로그에 남으므로,
+	// 헤더는 민감한 값만 해시로 치환한 뒤 기록합니다.
+	logger.Info("authorizer 이벤트 수신", "headers", logging.RedactHeaders(event.Headers))
 
 	// 이벤트에서 Authorization 헤더 값을 가져옵니다.
 	token := event.AuthorizationToken
 	if token == "" {
-		log.Println("Authorization 토큰이 없습니다.")
-		// 토큰이 없으면 즉시 접근 거부(Deny) 정책을 반환합니다.
-		return generatePolicy("user", events.IAMDeny, "*", "Anonymous"), fmt.Errorf("Unauthorized")
+		return deny(logger, start, corrID, "", "", "*", "missing_token"), fmt.Errorf("Unauthorized")
 	}
 
 	// 토큰에서 "Bearer " 접두사를 제거합니다.
@@ -32,22 +113,65 @@ func handler(ctx context.Context, event events.APIGatewayCustomAuthorizerRequest
 	}
 
 	// internal/auth 패키지의 VerifyToken 함수를 호출하여 토큰을 검증합니다.
-	userID, err := auth.VerifyToken(ctx, token)
+	userID, role, err := auth.VerifyToken(ctx, token)
+	if err != nil {
+		logger.Warn("토큰 검증 실패", "error", err)
+		return deny(logger, start, corrID, "", "", "*", "invalid_token"), fmt.Errorf("Unauthorized")
+	}
+	logger = logger.With("user_id", userID)
+
+	// MethodArn에서 HTTP 메서드와 리소스 경로를 뽑아 Casbin 정책과 매칭합니다.
+	httpMethod, resourcePath, err := parseMethodArn(event.MethodArn)
+	if err != nil {
+		logger.Error("MethodArn 파싱 실패", "error", err)
+		return deny(logger, start, corrID, userID, role, "*", "bad_method_arn"), fmt.Errorf("Unauthorized")
+	}
+
+	en, err := getEnforcer(ctx)
 	if err != nil {
-		log.Printf("토큰 검증 실패: %v", err)
-		// 검증에 실패하면 접근 거부(Deny) 정책을 반환합니다.
-		return generatePolicy("user", events.IAMDeny, "*", "Anonymous"), fmt.Errorf("Unauthorized")
+		logger.Error("Enforcer 초기화 실패", "error", err)
+		return deny(logger, start, corrID, userID, role, "*", "enforcer_init_failed"), fmt.Errorf("Unauthorized")
+	}
+
+	allowed, matchedSubject, err := en.Enforce(userID, role, httpMethod, resourcePath)
+	if err != nil {
+		logger.Error("Enforce 호출 실패", "error", err)
+		return deny(logger, start, corrID, userID, role, "*", "enforce_error"), fmt.Errorf("Unauthorized")
+	}
+	if !allowed {
+		return deny(logger, start, corrID, userID, role, event.MethodArn, "forbidden"), fmt.Errorf("Forbidden")
 	}
 
-	log.Printf("토큰이 성공적으로 검증되었습니다. 사용자 ID: %s", userID)
+	// 검증과 인가에 모두 성공하면, API Gateway에게 후속 Lambda 함수 호출을 허용(Allow)하는
+	// IAM 정책을 반환합니다. `principalId`로 사용자 ID를, `context` 맵에 role, correlation_id,
+	// 실제로 권한을 내준 정책 subject(role 또는 userID)를 담아 후속 Lambda에서 부가 검사와
+	// 로그 연계를 할 수 있게 합니다.
+	logger.Info("authorizer 판정", "decision", "Allow", "reason", "ok", "matched_subject", matchedSubject, "latency_ms", time.Since(start).Milliseconds())
+	return generatePolicy(userID, events.IAMAllow, event.MethodArn, userID, role, matchedSubject, corrID), nil
+}
 
-	// 검증에 성공하면, API Gateway에게 후속 Lambda 함수 호출을 허용(Allow)하는 IAM 정책을 반환합니다.
-	// 이 때, `principalId`로 사용자 ID를 전달하고, `context` 맵에 추가 정보를 담아 후속 Lambda에서 사용할 수 있게 합니다.
-	return generatePolicy(userID, events.IAMAllow, event.MethodArn, userID), nil
+// deny는 Deny 판정을 구조화 로그로 남기고, 해당하는 IAM Deny 정책을 만들어 돌려줍니다.
+func deny(logger *slog.Logger, start time.Time, corrID, userID, role, resource, reason string) events.APIGatewayCustomAuthorizerResponse {
+	principalID := userID
+	if principalID == "" {
+		principalID = "user"
+	}
+	logger.Warn("authorizer 판정", "decision", "Deny", "reason", reason, "latency_ms", time.Since(start).Milliseconds())
+	return generatePolicy(principalID, events.IAMDeny, resource, userID, role, "", corrID)
+}
+
+// parseMethodArn은 "arn:aws:execute-api:{region}:{account}:{apiId}/{stage}/{method}/{path}"
+// 형태의 MethodArn에서 HTTP 메서드와 리소스 경로를 추출합니다.
+func parseMethodArn(methodArn string) (httpMethod, resourcePath string, err error) {
+	parts := strings.SplitN(methodArn, "/", 4)
+	if len(parts) < 4 {
+		return "", "", fmt.Errorf("예상치 못한 MethodArn 형식: %s", methodArn)
+	}
+	return parts[2], "/" + parts[3], nil
 }
 
 // generatePolicy는 API Gateway Authorizer가 요구하는 형식의 IAM 정책 응답을 생성합니다.
-func generatePolicy(principalID, effect, resource, userID string) events.APIGatewayCustomAuthorizerResponse {
+func generatePolicy(principalID, effect, resource, userID, role, matchedSubject, corrID string) events.APIGatewayCustomAuthorizerResponse {
 	// 응답의 기본 구조를 생성합니다. PrincipalID는 요청의 주체를 나타냅니다.
 	authResponse := events.APIGatewayCustomAuthorizerResponse{PrincipalID: principalID}
 
@@ -68,7 +192,10 @@ func generatePolicy(principalID, effect, resource, userID string) events.APIGate
 	// 이 Authorizer를 통과한 후 호출될 Lambda 함수에게 전달할 추가 정보(컨텍스트)를 설정합니다.
 	// 후속 Lambda에서는 `event.RequestContext.Authorizer["user_id"]` 형태로 이 값을 꺼내 쓸 수 있습니다.
 	authResponse.Context = map[string]interface{}{
-		"user_id": userID,
+		"user_id":                    userID,
+		"role":                       role,
+		"matched_subject":            matchedSubject,
+		logging.CorrelationIDHeader:  corrID,
 	}
 
 	return authResponse
@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"tarot-backend/internal/logging"
 )
 
 // Response는 API Gateway에 반환할 응답의 구조체입니다.
@@ -16,22 +17,30 @@ type Response events.APIGatewayProxyResponse
 // handler 함수는 API Gateway로부터 요청을 받아 처리합니다.
 // 이 함수는 Lambda Authorizer 뒤에서 실행되는 것을 전제로 합니다.
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (Response, error) {
-	// 어떤 요청이 들어왔는지 로그를 남깁니다.
-	log.Printf("Request received: %+v", request)
-
-	// Lambda Authorizer가 `context`에 담아준 사용자 ID를 추출합니다.
+	// Lambda Authorizer가 `context`에 담아준 사용자 ID/role/correlation_id를 추출합니다.
 	// `request.RequestContext.Authorizer`는 map[string]interface{} 타입입니다.
 	authorizerContext, ok := request.RequestContext.Authorizer.(map[string]interface{})
 	if !ok {
+		logging.New().Warn("authorizer context가 없습니다")
 		return response(401, `{"error":"Authorizer context is missing"}`), nil
 	}
 
+	corrID, _ := authorizerContext[logging.CorrelationIDHeader].(string)
+	logger := logging.New().With("correlation_id", corrID)
+	logger.Info("request received",
+		"path", request.Path,
+		"method", request.HTTPMethod,
+		"headers", logging.RedactHeaders(request.Headers),
+	)
+
 	userID, ok := authorizerContext["user_id"].(string)
 	if !ok || userID == "" {
+		logger.Warn("authorizer context에 user_id가 없습니다")
 		return response(401, `{"error":"User ID not found in authorizer context"}`), nil
 	}
+	logger = logger.With("user_id", userID)
 
-	log.Printf("Successfully authenticated user ID: %s", userID)
+	logger.Info("authentication successful")
 
 	// (미래 확장)
 	// 이 userID를 사용하여 Supabase DB에서 사용자의 프로필 정보(닉네임, 크레딧 등)를 조회할 수 있습니다.
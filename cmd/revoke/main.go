@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"tarot-backend/internal/auth"
+	"tarot-backend/internal/logging"
+)
+
+// Response는 API Gateway에 반환할 응답의 구조체입니다.
+type Response events.APIGatewayProxyResponse
+
+// handler 함수는 "로그아웃" API의 실체입니다. Authorization 헤더로 전달된 토큰을
+// 검증한 뒤 denylist에 기록해, exp가 지나기 전이라도 더 이상 유효하지 않게 만듭니다.
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (Response, error) {
+	corrID := logging.CorrelationID(request.Headers)
+	logger := logging.New().With("correlation_id", corrID)
+	logger.Info("request received",
+		"path", request.Path,
+		"method", request.HTTPMethod,
+		"headers", logging.RedactHeaders(request.Headers),
+	)
+
+	token := request.Headers["Authorization"]
+	if token == "" {
+		token = request.Headers["authorization"]
+	}
+	if token == "" {
+		return response(401, `{"error":"Authorization header is missing"}`), nil
+	}
+
+	if err := auth.Revoke(ctx, token); err != nil {
+		logger.Warn("토큰 무효화 실패", "error", err)
+		return response(401, `{"error":"token could not be revoked"}`), nil
+	}
+
+	return response(200, `{"message":"logged out"}`), nil
+}
+
+// response 함수는 HTTP 상태 코드와 응답 본문을 받아
+// API Gateway가 요구하는 형식의 Response 구조체를 생성합니다.
+func response(statusCode int, body string) Response {
+	headers := map[string]string{
+		"Content-Type":                 "application/json",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "POST, OPTIONS",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+	}
+
+	return Response{
+		StatusCode:      statusCode,
+		Headers:         headers,
+		Body:            body,
+		IsBase64Encoded: false,
+	}
+}
+
+// main 함수는 Lambda 실행 환경의 시작점입니다.
+func main() {
+	lambda.Start(handler)
+}
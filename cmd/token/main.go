@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"tarot-backend/internal/auth"
+	"tarot-backend/internal/logging"
+	"tarot-backend/internal/tokens"
+)
+
+// Response는 API Gateway에 반환할 응답의 구조체입니다.
+type Response events.APIGatewayProxyResponse
+
+var (
+	refreshTableName = os.Getenv("REFRESH_TOKEN_TABLE_NAME")
+	accessTokenTTL   = envDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTokenTTL  = envDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+
+	svc     *tokens.Service
+	svcOnce sync.Once
+	svcErr  error
+)
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// getService는 tokens.Service를 cold start당 한 번만 초기화합니다.
+func getService(ctx context.Context) (*tokens.Service, error) {
+	svcOnce.Do(func() {
+		cfg, err := config.LoadDefaultAWSConfig(ctx)
+		if err != nil {
+			svcErr = err
+			return
+		}
+		svc = tokens.NewService(dynamodb.NewFromConfig(cfg), refreshTableName, accessTokenTTL, refreshTokenTTL)
+	})
+	return svc, svcErr
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// handler는 /token/issue, /token/refresh, /token/introspect 세 경로를 처리합니다.
+// API Gateway가 세 경로를 같은 Lambda에 붙이도록 구성돼 있다는 전제입니다.
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (Response, error) {
+	corrID := logging.CorrelationID(request.Headers)
+	logger := logging.New().With("correlation_id", corrID)
+	// request.Body에는 refresh_token이 평문으로 담겨 있으므로 본문은 로그에 남기지
+	// 않고, 헤더는 Authorization만 해시로 치환해 기록합니다.
+	logger.Info("request received",
+		"path", request.Path,
+		"method", request.HTTPMethod,
+		"headers", logging.RedactHeaders(request.Headers),
+	)
+
+	svc, err := getService(ctx)
+	if err != nil {
+		logger.Error("tokens.Service 초기화 실패", "error", err)
+		return response(500, `{"error":"internal server error"}`), nil
+	}
+
+	if strings.HasSuffix(request.Path, "/issue") {
+		return issue(ctx, logger, svc, request)
+	}
+
+	var body refreshRequest
+	if err := json.Unmarshal([]byte(request.Body), &body); err != nil || body.RefreshToken == "" {
+		return response(400, `{"error":"refresh_token is required"}`), nil
+	}
+
+	switch {
+	case strings.HasSuffix(request.Path, "/introspect"):
+		return introspect(ctx, logger, svc, body.RefreshToken)
+	default:
+		return refresh(ctx, logger, svc, body.RefreshToken)
+	}
+}
+
+// issue는 Supabase 로그인 직후 호출되는 "/token/issue" 경로의 실체입니다. Authorization
+// 헤더로 전달된 Supabase 세션 토큰을 검증해 userID/role을 확인한 뒤, 새 refresh token
+// family를 시작해 우리 서비스가 자체 발급/로테이션하는 access/refresh token 쌍을 돌려줍니다.
+// 이 경로가 없으면 tokens.Service.IssueInitial이 호출될 길이 없어 Refresh가 평생 "유효하지
+// 않은 refresh token"만 돌려주게 됩니다.
+func issue(ctx context.Context, logger *slog.Logger, svc *tokens.Service, request events.APIGatewayProxyRequest) (Response, error) {
+	token := request.Headers["Authorization"]
+	if token == "" {
+		token = request.Headers["authorization"]
+	}
+	if token == "" {
+		return response(401, `{"error":"Authorization header is missing"}`), nil
+	}
+
+	userID, role, err := auth.VerifyToken(ctx, token)
+	if err != nil {
+		logger.Warn("issue 요청의 토큰 검증 실패", "error", err)
+		return response(401, `{"error":"invalid token"}`), nil
+	}
+
+	pair, err := svc.IssueInitial(ctx, userID, role)
+	if err != nil {
+		logger.Error("refresh token family 발급 실패", "error", err)
+		return response(500, `{"error":"internal server error"}`), nil
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"access_token":  pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+	})
+	return response(200, string(body)), nil
+}
+
+func refresh(ctx context.Context, logger *slog.Logger, svc *tokens.Service, refreshToken string) (Response, error) {
+	pair, err := svc.Refresh(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, tokens.ErrReuseDetected) {
+			logger.Warn("refresh token 재사용 감지", "error", err)
+			return response(401, `{"error":"refresh token reuse detected, session revoked"}`), nil
+		}
+		if errors.Is(err, tokens.ErrInvalidRefreshToken) {
+			return response(401, `{"error":"invalid or expired refresh token"}`), nil
+		}
+		logger.Error("refresh token 로테이션 실패", "error", err)
+		return response(500, `{"error":"internal server error"}`), nil
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"access_token":  pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+	})
+	return response(200, string(body)), nil
+}
+
+func introspect(ctx context.Context, logger *slog.Logger, svc *tokens.Service, refreshToken string) (Response, error) {
+	result, err := svc.Introspect(ctx, refreshToken)
+	if err != nil {
+		logger.Error("introspect 실패", "error", err)
+		return response(500, `{"error":"internal server error"}`), nil
+	}
+
+	body, _ := json.Marshal(result)
+	return response(200, string(body)), nil
+}
+
+// response 함수는 HTTP 상태 코드와 응답 본문을 받아
+// API Gateway가 요구하는 형식의 Response 구조체를 생성합니다.
+func response(statusCode int, body string) Response {
+	headers := map[string]string{
+		"Content-Type":                 "application/json",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "POST, OPTIONS",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+	}
+
+	return Response{
+		StatusCode:      statusCode,
+		Headers:         headers,
+		Body:            body,
+		IsBase64Encoded: false,
+	}
+}
+
+// main 함수는 Lambda 실행 환경의 시작점입니다.
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// verifiedToken은 검증을 한 번 통과한 토큰에 대해 캐싱해두는 결과입니다.
+type verifiedToken struct {
+	userID string
+	role   string
+	exp    time.Time
+}
+
+// positiveCacheEntry는 LRU 연결 리스트의 한 노드에 저장되는 값입니다.
+type positiveCacheEntry struct {
+	tokenHash string
+	token     verifiedToken
+	cachedAt  time.Time
+}
+
+// positiveCache는 {tokenHash -> 검증 결과}를 TTL과 LRU 용량 상한을 같이 적용해
+// 캐싱합니다. 같은 웜 Lambda 컨테이너에서 짧은 시간 안에 같은 토큰으로 반복 호출되는
+// 경우, 서명 검증 + SSM 조회 + denylist 조회를 전부 건너뛰게 해줍니다.
+type positiveCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	capacity int
+
+	order   *list.List // front = 가장 최근 사용
+	entries map[string]*list.Element
+}
+
+func newPositiveCache(ttl time.Duration, capacity int) *positiveCache {
+	return &positiveCache{
+		ttl:      ttl,
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get은 캐시에서 tokenHash를 찾습니다. TTL이 지났거나 원본 토큰이 이미 만료됐다면
+// 항목을 제거하고 miss로 취급합니다.
+func (c *positiveCache) get(tokenHash string) (verifiedToken, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[tokenHash]
+	if !ok {
+		return verifiedToken{}, false
+	}
+	entry := elem.Value.(*positiveCacheEntry)
+
+	now := time.Now()
+	if now.Sub(entry.cachedAt) > c.ttl || now.After(entry.token.exp) {
+		c.order.Remove(elem)
+		delete(c.entries, tokenHash)
+		return verifiedToken{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.token, true
+}
+
+// delete는 tokenHash 항목을 캐시에서 즉시 제거합니다. 토큰을 revoke한 직후, 같은 웜
+// 컨테이너가 TTL이 끝나기 전까지 무효화된 토큰을 유효하다고 계속 캐싱해 돌려주는 것을
+// 막기 위해 사용합니다.
+func (c *positiveCache) delete(tokenHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[tokenHash]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, tokenHash)
+	}
+}
+
+// put은 검증 결과를 캐싱하고, 용량을 초과하면 가장 오래 사용되지 않은 항목을 내쫓습니다.
+func (c *positiveCache) put(tokenHash string, token verifiedToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[tokenHash]; ok {
+		elem.Value = &positiveCacheEntry{tokenHash: tokenHash, token: token, cachedAt: time.Now()}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&positiveCacheEntry{tokenHash: tokenHash, token: token, cachedAt: time.Now()})
+	c.entries[tokenHash] = elem
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*positiveCacheEntry).tokenHash)
+	}
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/redis/go-redis/v9"
+)
+
+// DenylistStore는 로그아웃/강제 탈퇴 등으로 무효화된 토큰을 조회/기록하는 저장소를
+// 추상화합니다. VerifyToken은 서명 검증 이후 이 저장소에 토큰 해시가 올라와 있는지
+// 확인하고, cmd/revoke는 이 저장소에 해시를 기록합니다.
+type DenylistStore interface {
+	// IsRevoked는 tokenHash가 무효화 목록에 있는지 확인합니다.
+	IsRevoked(ctx context.Context, tokenHash string) (bool, error)
+	// Revoke는 tokenHash를 expiresAt까지 무효화 목록에 기록합니다. 원본 토큰의 exp를
+	// 그대로 넘기면, 어차피 만료될 토큰을 저장소가 불필요하게 오래 들고 있지 않습니다.
+	Revoke(ctx context.Context, tokenHash string, expiresAt time.Time) error
+}
+
+// TokenHash는 토큰 원문(header.payload.signature) 전체를 SHA-256 해시한 값을 반환합니다.
+// jti 같은 클레임은 서명 검증 전에는 누구나 읽고 위조할 수 있으므로, 위조가 불가능한
+// 서명까지 포함한 원문 전체를 해시 입력으로 써야만 VerifyToken의 positive cache 키나
+// denylist 키로 안전하게 쓸 수 있습니다.
+func TokenHash(tokenString string) string {
+	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+	return sha256Hex(tokenString)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// DynamoDBDenylist는 DynamoDB 테이블에 {token_hash (PK), exp (TTL 속성)}로 저장하는
+// DenylistStore 구현체입니다. exp에 TTL 속성을 지정해두면, 토큰이 어차피 만료된 뒤에는
+// DynamoDB가 자동으로 행을 정리해주므로 별도 청소 배치가 필요 없습니다.
+type DynamoDBDenylist struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBDenylist는 tableName 테이블을 사용하는 DynamoDBDenylist를 생성합니다.
+func NewDynamoDBDenylist(client *dynamodb.Client, tableName string) *DynamoDBDenylist {
+	return &DynamoDBDenylist{client: client, tableName: tableName}
+}
+
+type denylistItem struct {
+	TokenHash string `dynamodbav:"token_hash"`
+	Exp       int64  `dynamodbav:"exp"`
+}
+
+// IsRevoked는 token_hash로 GetItem을 호출해 행이 존재하는지 확인합니다.
+func (d *DynamoDBDenylist) IsRevoked(ctx context.Context, tokenHash string) (bool, error) {
+	key, err := attributevalue.MarshalMap(map[string]string{"token_hash": tokenHash})
+	if err != nil {
+		return false, fmt.Errorf("denylist 조회 키 마샬링 실패: %w", err)
+	}
+
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key:       key,
+	})
+	if err != nil {
+		return false, fmt.Errorf("DynamoDB denylist 조회 실패: %w", err)
+	}
+	return out.Item != nil, nil
+}
+
+// Revoke는 token_hash 행을 PutItem으로 기록합니다. exp는 DynamoDB TTL 속성이므로
+// 반드시 Unix epoch 초 단위여야 합니다.
+func (d *DynamoDBDenylist) Revoke(ctx context.Context, tokenHash string, expiresAt time.Time) error {
+	item, err := attributevalue.MarshalMap(denylistItem{TokenHash: tokenHash, Exp: expiresAt.Unix()})
+	if err != nil {
+		return fmt.Errorf("denylist 항목 마샬링 실패: %w", err)
+	}
+
+	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("DynamoDB denylist 기록 실패: %w", err)
+	}
+	return nil
+}
+
+// RedisDenylist는 ElastiCache(Redis)에 token_hash를 키로, 값 "1"을 TTL과 함께 SET으로
+// 저장하는 DenylistStore 구현체입니다. DynamoDB보다 지연 시간이 짧아 동시 요청이 많은
+// 환경에 적합합니다.
+type RedisDenylist struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisDenylist는 client를 사용하는 RedisDenylist를 생성합니다. keyPrefix는 다른
+// 용도의 키와 네임스페이스를 분리하기 위한 접두사입니다(예: "denylist:").
+func NewRedisDenylist(client *redis.Client, keyPrefix string) *RedisDenylist {
+	return &RedisDenylist{client: client, keyPrefix: keyPrefix}
+}
+
+func (r *RedisDenylist) key(tokenHash string) string {
+	return r.keyPrefix + tokenHash
+}
+
+// IsRevoked는 키가 존재하는지 EXISTS로 확인합니다.
+func (r *RedisDenylist) IsRevoked(ctx context.Context, tokenHash string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.key(tokenHash)).Result()
+	if err != nil {
+		return false, fmt.Errorf("Redis denylist 조회 실패: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Revoke는 expiresAt까지 남은 시간만큼 TTL을 건 키를 SET합니다. 이미 만료된 시각이면
+// 즉시 만료되도록 최소 1초의 TTL을 사용합니다.
+func (r *RedisDenylist) Revoke(ctx context.Context, tokenHash string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+	if err := r.client.Set(ctx, r.key(tokenHash), "1", ttl).Err(); err != nil {
+		return fmt.Errorf("Redis denylist 기록 실패: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IssueAccessToken은 internal/tokens의 refresh-token 로테이션이 새 access token을 발급할
+// 때 사용하는 헬퍼입니다. SSM에 저장된 것과 같은 공유 비밀로 서명하고, 기존 claims 구조를
+// 그대로 채우므로, JWT_MODE=hs256(기본값)인 authorizer는 이 토큰과 Supabase가 발급한 토큰을
+// 구분할 필요가 없습니다. JWT_MODE=jwks에서는 JWKSProvider가 HS256 토큰을 거부합니다.
+func IssueAccessToken(ctx context.Context, userID, role string, ttl time.Duration) (string, error) {
+	secret, err := getJWTSecret(ctx)
+	if err != nil {
+		return "", fmt.Errorf("JWT 시크릿을 가져오는 데 실패했습니다: %w", err)
+	}
+
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", fmt.Errorf("jti 생성 실패: %w", err)
+	}
+
+	now := time.Now()
+	c := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		UserUID:  userID,
+		UserRole: role,
+	}
+
+	// JWT_ISSUER/JWT_AUDIENCE가 설정된 배포에서는 VerifyToken이 모든 토큰(자체 발급분
+	// 포함)에 validateIssuerAndAudience를 적용하므로, 여기서도 같은 허용 목록의 첫 값으로
+	// iss/aud를 채워 방금 발급한 토큰이 스스로의 검증에 막히지 않게 합니다.
+	if len(jwtIssuerAllowList) > 0 {
+		c.Issuer = jwtIssuerAllowList[0]
+	}
+	if len(jwtAudienceAllowList) > 0 {
+		c.Audience = jwt.ClaimStrings(jwtAudienceAllowList)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("access token 서명 실패: %w", err)
+	}
+	return signed, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
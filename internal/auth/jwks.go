@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwk는 JWKS 응답에 담긴 개별 JSON Web Key 중, RSA/EC 공개키 검증에 필요한 필드만
+// 옮겨 담은 구조체입니다. Supabase, Auth0, Cognito 등 대부분의 OIDC 발급자가 이 필드들을
+// 동일한 이름으로 내려줍니다.
+type jwk struct {
+	Kty string `json:"kty"` // "RSA" 또는 "EC"
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksResponse struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKSProvider는 JWKS 엔드포인트에서 공개키 집합을 받아와 메모리에 TTL과 함께 캐싱하고,
+// 토큰 헤더의 "kid" 값으로 알맞은 키를 찾아주는 KeyProvider 구현체입니다. 키 로테이션 중
+// 새 kid가 들어오면 캐시를 한 번 강제로 새로고침한 뒤에만 실패로 처리합니다.
+type JWKSProvider struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	keysByKid map[string]interface{} // kid -> *rsa.PublicKey 또는 *ecdsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSProvider는 jwksURL에서 키를 가져오는 JWKSProvider를 생성합니다. ttl이 0 이하이면
+// 기본값(5분)을 사용합니다.
+func NewJWKSProvider(jwksURL string, ttl time.Duration) *JWKSProvider {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &JWKSProvider{
+		url:    jwksURL,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Key는 token.Header["kid"]에 해당하는 공개키를 반환합니다. 캐시가 비어 있거나 만료됐으면
+// 먼저 새로고침하고, 그래도 kid를 찾지 못하면 로테이션을 의심해 한 번 더 강제 새로고침한
+// 뒤 재시도합니다.
+func (p *JWKSProvider) Key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+	case *jwt.SigningMethodECDSA:
+	default:
+		return nil, fmt.Errorf("예상치 못한 서명 알고리즘: %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("토큰 헤더에 kid가 없습니다")
+	}
+
+	key, err := p.lookup(ctx, kid, false)
+	if err == nil {
+		return key, nil
+	}
+
+	// kid를 찾지 못했다면 키 로테이션일 수 있으므로, 캐시를 강제로 한 번 새로고침한 뒤
+	// 마지막으로 한 번 더 시도합니다.
+	return p.lookup(ctx, kid, true)
+}
+
+func (p *JWKSProvider) lookup(ctx context.Context, kid string, forceRefresh bool) (interface{}, error) {
+	p.mu.Lock()
+	stale := forceRefresh || p.keysByKid == nil || time.Since(p.fetchedAt) > p.ttl
+	p.mu.Unlock()
+
+	if stale {
+		if err := p.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key, ok := p.keysByKid[kid]
+	if !ok {
+		return nil, fmt.Errorf("JWKS에서 kid %q를 찾을 수 없습니다", kid)
+	}
+	return key, nil
+}
+
+// refresh는 JWKS 엔드포인트를 호출해 전체 키 집합을 다시 받아오고 캐시를 교체합니다.
+func (p *JWKSProvider) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("JWKS 요청 생성 실패: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("JWKS 엔드포인트 호출 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("JWKS 응답 읽기 실패: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS 엔드포인트가 비정상 응답을 반환했습니다: %d", resp.StatusCode)
+	}
+
+	var parsed jwksResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return fmt.Errorf("JWKS 응답 파싱 실패: %w", err)
+	}
+
+	keysByKid := make(map[string]interface{}, len(parsed.Keys))
+	for _, k := range parsed.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			// 파싱할 수 없는 키 하나 때문에 전체 JWKS를 버리지 않고 건너뜁니다.
+			continue
+		}
+		keysByKid[k.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keysByKid = keysByKid
+	p.fetchedAt = time.Now()
+	p.mu.Unlock()
+	return nil
+}
+
+// publicKey는 JWK 필드를 실제 crypto 공개키 타입으로 변환합니다.
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("RSA n 디코딩 실패: %w", err)
+		}
+		eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("RSA e 디코딩 실패: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nBytes),
+			E: int(new(big.Int).SetBytes(eBytes).Int64()),
+		}, nil
+	case "EC":
+		curve, err := ecdsaCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("EC x 디코딩 실패: %w", err)
+		}
+		yBytes, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("EC y 디코딩 실패: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(xBytes),
+			Y:     new(big.Int).SetBytes(yBytes),
+		}, nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 kty: %s", k.Kty)
+	}
+}
+
+func ecdsaCurve(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 curve: %s", crv)
+	}
+}
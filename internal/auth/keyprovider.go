@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyProvider는 JWT 서명 검증에 사용할 키를 제공하는 공급자를 추상화합니다.
+// HS256 공유 비밀 방식(HMACKeyProvider)과 JWKS 기반 비대칭키 방식(JWKSProvider)을
+// 동일한 인터페이스 뒤에 숨겨, VerifyToken이 둘 중 어느 쪽을 쓰든 신경 쓰지 않게 합니다.
+type KeyProvider interface {
+	// Key는 파싱 중인 토큰의 서명 알고리즘(token.Method)과 헤더(token.Header["kid"] 등)를
+	// 보고, jwt.Keyfunc에 그대로 반환할 수 있는 검증용 키를 돌려줍니다.
+	Key(ctx context.Context, token *jwt.Token) (interface{}, error)
+}
+
+// HMACKeyProvider는 기존 동작(SSM Parameter Store에 저장된 공유 비밀로 HS256 토큰을
+// 검증하는 방식)을 KeyProvider 인터페이스로 감싼 구현체입니다. Supabase의 레거시 HS256
+// JWT를 그대로 지원하기 위해 유지합니다.
+type HMACKeyProvider struct{}
+
+// Key는 HMAC 서명 알고리즘인지 확인한 뒤 SSM에서 가져온(캐시된) 공유 비밀을 반환합니다.
+func (HMACKeyProvider) Key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("예상치 못한 서명 알고리즘: %v", token.Header["alg"])
+	}
+
+	secret, err := getJWTSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(secret), nil
+}
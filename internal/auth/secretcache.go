@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// jwtSecretParamPath는 JWT 시크릿이 저장된 AWS Systems Manager Parameter Store의
+	// 경로입니다. 이 값은 Lambda 함수의 환경 변수 'JWT_SECRET_PARAM_PATH'를 통해
+	// 주입되어야 합니다.
+	jwtSecretParamPath = os.Getenv("JWT_SECRET_PARAM_PATH")
+
+	// jwtSecretTTL이 지난 캐시는 "stale"로 간주되어 백그라운드 새로고침을 트리거합니다.
+	// 기본값 5분이며, JWT_SECRET_TTL 환경 변수(예: "2m")로 조정할 수 있습니다.
+	jwtSecretTTL = envDurationOrDefault("JWT_SECRET_TTL", 5*time.Minute)
+
+	// secretsExtensionPort는 AWS Parameters and Secrets Lambda Extension이 리스닝하는
+	// 로컬 포트입니다. Lambda 레이어로 확장을 붙이면 기본값 2773을 그대로 씁니다.
+	secretsExtensionPort = envOrDefault("PARAMETERS_SECRETS_EXTENSION_HTTP_PORT", "2773")
+
+	secretState atomic.Pointer[secretSnapshot]
+	secretGroup singleflight.Group
+)
+
+// secretSnapshot은 어느 시점에 캐시된 JWT 시크릿 값입니다. current가 최신 값이고,
+// previous는 바로 직전 값입니다(로테이션 중에도 방금 전에 발급된 토큰이 계속
+// 유효하도록 검증 시 둘 다 시도합니다).
+type secretSnapshot struct {
+	current   string
+	previous  string
+	fetchedAt time.Time
+}
+
+func envDurationOrDefault(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// getJWTSecret은 현재 유효한 JWT 시크릿을 반환합니다. 캐시가 비어 있으면(콜드 스타트)
+// 동기적으로 한 번 가져오고, 캐시가 있지만 JWT_SECRET_TTL보다 오래됐으면 stale 값을
+// 즉시 반환하면서 singleflight로 백그라운드 새로고침을 한 번만 트리거합니다. 이렇게 하면
+// 한 프로세스 안에서 stale 새로고침이 한 번의 조회로 모이고, 로테이션된 시크릿을 컨테이너
+// 재시작 없이 반영할 수 있습니다. singleflight는 프로세스 단위이므로 컨테이너 간 호출은
+// 합쳐지지 않으며, 콜드 스타트의 첫 조회는 singleflight를 거치지 않습니다.
+func getJWTSecret(ctx context.Context) (string, error) {
+	snap := secretState.Load()
+	if snap == nil {
+		fresh, err := refreshSecret(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fresh.current, nil
+	}
+
+	if time.Since(snap.fetchedAt) > jwtSecretTTL {
+		// DoChan은 이미 진행 중인 새로고침이 있으면 합류만 하고, 없으면 백그라운드로
+		// 시작시킵니다. 결과를 기다리지 않으므로 호출자는 stale 값을 바로 받습니다.
+		secretGroup.DoChan("refresh", func() (interface{}, error) {
+			return refreshSecret(context.Background())
+		})
+	}
+
+	return snap.current, nil
+}
+
+// previousJWTSecret은 가장 최근 로테이션 이전에 쓰이던 시크릿을 반환합니다(없으면 빈
+// 문자열). VerifyToken이 현재 시크릿으로 검증에 실패했을 때 재시도용으로 사용합니다.
+func previousJWTSecret() string {
+	snap := secretState.Load()
+	if snap == nil {
+		return ""
+	}
+	return snap.previous
+}
+
+// refreshSecret은 실제로 시크릿 값을 가져와 캐시를 교체합니다. 값이 바뀌었다면 이전
+// current를 previous로 승격시켜, 로테이션 직후의 짧은 창 동안에도 구 토큰이 계속
+// 검증되게 합니다.
+func refreshSecret(ctx context.Context) (*secretSnapshot, error) {
+	value, err := fetchSecretValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	old := secretState.Load()
+	snap := &secretSnapshot{current: value, fetchedAt: time.Now()}
+	switch {
+	case old == nil:
+		// 최초 fetch: previous 없음
+	case old.current != value:
+		snap.previous = old.current
+	default:
+		snap.previous = old.previous
+	}
+
+	secretState.Store(snap)
+	return snap, nil
+}
+
+// fetchSecretValue는 AWS Parameters and Secrets Lambda Extension을 우선 사용하고,
+// 확장이 없는 환경(로컬 실행, 확장 레이어 미부착 등)에서는 SSM SDK로 폴백합니다.
+func fetchSecretValue(ctx context.Context) (string, error) {
+	if jwtSecretParamPath == "" {
+		return "", errors.New("JWT_SECRET_PARAM_PATH 환경 변수가 설정되지 않았습니다")
+	}
+
+	if os.Getenv("AWS_SESSION_TOKEN") != "" {
+		value, err := fetchSecretFromExtension(ctx)
+		if err == nil {
+			return value, nil
+		}
+		log.Printf("Parameters & Secrets 확장 조회 실패, SSM SDK로 폴백합니다: %v", err)
+	}
+
+	return fetchSecretFromSSM(ctx)
+}
+
+// fetchSecretFromExtension은 Lambda 실행 환경에 함께 구동되는 Parameters and Secrets
+// Lambda Extension의 로컬 HTTP 엔드포인트를 호출합니다. 확장이 AWS_SESSION_TOKEN으로
+// 인증을 대신 처리해주므로, 매 컨테이너가 직접 SSM API를 호출하지 않아도 됩니다.
+func fetchSecretFromExtension(ctx context.Context) (string, error) {
+	endpoint := fmt.Sprintf(
+		"http://localhost:%s/systemsmanager/parameters/get?name=%s&withDecryption=true",
+		secretsExtensionPort, url.QueryEscape(jwtSecretParamPath),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("확장 요청 생성 실패: %w", err)
+	}
+	req.Header.Set("X-Aws-Parameters-Secrets-Token", os.Getenv("AWS_SESSION_TOKEN"))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("확장 호출 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("확장이 비정상 응답을 반환했습니다: %d", resp.StatusCode)
+	}
+
+	var parsed struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("확장 응답 파싱 실패: %w", err)
+	}
+	if parsed.Parameter.Value == "" {
+		return "", errors.New("확장 응답에 파라미터 값이 없습니다")
+	}
+	return parsed.Parameter.Value, nil
+}
+
+// fetchSecretFromSSM은 AWS SDK로 SSM Parameter Store를 직접 호출합니다(확장이 없는
+// 환경을 위한 폴백 경로).
+func fetchSecretFromSSM(ctx context.Context) (string, error) {
+	cfg, err := config.LoadDefaultAWSConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("AWS 설정 로딩 실패: %w", err)
+	}
+	client := ssm.NewFromConfig(cfg)
+
+	paramOutput, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           &jwtSecretParamPath,
+		WithDecryption: true, // SecureString을 복호화하기 위해 true로 설정
+	})
+	if err != nil {
+		return "", fmt.Errorf("Parameter Store에서 JWT 시크릿을 가져오는 데 실패했습니다: %w", err)
+	}
+	if paramOutput.Parameter == nil || paramOutput.Parameter.Value == nil {
+		return "", errors.New("JWT 시크릿 파라미터 값이 존재하지 않습니다")
+	}
+	return *paramOutput.Parameter.Value, nil
+}
@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 )
 
 // claims는 Supabase가 발행하는 JWT의 payload 구조를 나타냅니다.
@@ -25,74 +27,102 @@ type claims struct {
 }
 
 var (
-	// 한 번 가져온 JWT 시크릿을 캐싱하여, Lambda가 재실행될 때마다 Parameter Store를 호출하는 것을 방지합니다.
-	jwtSecret string
-	// 동시성 문제 방지를 위한 뮤텍스
-	secretMux sync.Mutex
-	// SSM 클라이언트 인스턴스
-	ssmClient *ssm.Client
-	// JWT 시크릿이 저장된 AWS Systems Manager Parameter Store의 경로입니다.
-	// 이 값은 Lambda 함수의 환경 변수 'JWT_SECRET_PARAM_PATH'를 통해 주입되어야 합니다.
-	jwtSecretParamPath = os.Getenv("JWT_SECRET_PARAM_PATH")
+	// jwtMode는 VerifyToken이 사용할 키 공급 방식을 고릅니다. "hs256"(기본값)이면 기존처럼
+	// SSM에 저장된 공유 비밀을 쓰고, "jwks"면 JWT_JWKS_URL에서 공개키 집합을 받아옵니다.
+	jwtMode = envOrDefault("JWT_MODE", "hs256")
+	// jwtJWKSURL은 JWT_MODE=jwks일 때 공개키 집합(JWKS)을 받아올 엔드포인트입니다.
+	jwtJWKSURL = os.Getenv("JWT_JWKS_URL")
+	// jwtIssuerAllowList / jwtAudienceAllowList는 쉼표로 구분된 허용 iss/aud 목록입니다.
+	// 비어 있으면 해당 클레임 검증을 건너뜁니다.
+	jwtIssuerAllowList   = splitCSV(os.Getenv("JWT_ISSUER"))
+	jwtAudienceAllowList = splitCSV(os.Getenv("JWT_AUDIENCE"))
+
+	// keyProvider는 cold start 시 jwtMode에 따라 한 번만 초기화되는 KeyProvider입니다.
+	keyProvider     KeyProvider
+	keyProviderOnce sync.Once
+
+	// denylistBackend는 "dynamodb"(기본값) 또는 "redis"입니다. 테이블/엔드포인트 환경
+	// 변수가 비어 있으면 denylist 조회 자체를 건너뜁니다(로컬 개발 환경 등).
+	denylistBackend   = envOrDefault("DENYLIST_BACKEND", "dynamodb")
+	denylistTableName = os.Getenv("DENYLIST_TABLE_NAME")
+	denylistRedisAddr = os.Getenv("DENYLIST_REDIS_ADDR")
+	denylistStore     DenylistStore
+	denylistStoreMu   sync.Mutex
+
+	// positiveTTL / positiveCapacity는 검증에 성공한 토큰을 얼마나/몇 개나
+	// 인메모리에 캐싱할지 정합니다. 값은 60초, 1000개로 고정돼 있습니다.
+	positiveTTL      = 60 * time.Second
+	positiveCapacity = 1000
+	tokenCache       = newPositiveCache(positiveTTL, positiveCapacity)
 )
 
-// getSSMClient는 AWS SSM 클라이언트를 초기화하고 반환합니다.
-// 싱글톤 패턴을 사용하여, 한 번 생성된 클라이언트를 계속 재사용합니다.
-func getSSMClient(ctx context.Context) (*ssm.Client, error) {
-	if ssmClient == nil {
+// getDenylistStore는 환경 변수에 맞는 DenylistStore를 생성해 재사용합니다. 관련 환경
+// 변수가 비어 있으면 nil을 반환하고, 호출부는 denylist 검사를 건너뜁니다. AWS 설정 로딩처럼
+// 실패할 수 있는 작업이므로 sync.Once로 한 번만 시도하지 않고, 아직 초기화에 성공하지
+// 못한 동안은 호출될 때마다 다시 시도합니다 — 그렇지 않으면 콜드 스타트 때의 일시적인
+// 오류가 해당 컨테이너의 남은 수명 동안 denylist 검사를 영구히 꺼버리게 됩니다.
+func getDenylistStore(ctx context.Context) DenylistStore {
+	denylistStoreMu.Lock()
+	defer denylistStoreMu.Unlock()
+
+	if denylistStore != nil {
+		return denylistStore
+	}
+
+	switch {
+	case denylistBackend == "redis" && denylistRedisAddr != "":
+		client := redis.NewClient(&redis.Options{Addr: denylistRedisAddr})
+		denylistStore = NewRedisDenylist(client, "denylist:")
+	case denylistBackend == "dynamodb" && denylistTableName != "":
 		cfg, err := config.LoadDefaultAWSConfig(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("AWS 설정 로딩 실패: %w", err)
+			log.Printf("denylist용 AWS 설정 로딩 실패: %v", err)
+			return nil
 		}
-		ssmClient = ssm.NewFromConfig(cfg)
+		denylistStore = NewDynamoDBDenylist(dynamodb.NewFromConfig(cfg), denylistTableName)
 	}
-	return ssmClient, nil
+	return denylistStore
 }
 
-// getJWTSecret는 AWS Systems Manager Parameter Store에서 JWT 시크릿을 가져옵니다.
-// 가져온 시크릿은 전역 변수 'jwtSecret'에 캐싱하여 불필요한 API 호출을 줄입니다.
-func getJWTSecret(ctx context.Context) (string, error) {
-	// 여러 요청이 동시에 시크릿을 가져오려 할 때의 경쟁 상태를 방지합니다.
-	secretMux.Lock()
-	defer secretMux.Unlock()
-
-	// 시크릿이 이미 캐시되어 있다면, 즉시 반환합니다.
-	if jwtSecret != "" {
-		return jwtSecret, nil
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
+	return fallback
+}
 
-	// 환경변수가 설정되지 않았다면 에러를 반환합니다.
-	if jwtSecretParamPath == "" {
-		return "", errors.New("JWT_SECRET_PARAM_PATH 환경 변수가 설정되지 않았습니다")
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
 	}
-
-	client, err := getSSMClient(ctx)
-	if err != nil {
-		return "", fmt.Errorf("SSM 클라이언트 생성 실패: %w", err)
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
 	}
+	return out
+}
 
-	// Parameter Store에서 SecureString 타입의 파라미터를 가져옵니다.
-	paramOutput, err := client.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           &jwtSecretParamPath,
-		WithDecryption: true, // SecureString을 복호화하기 위해 true로 설정
+// getKeyProvider는 jwtMode에 맞는 KeyProvider를 cold start당 한 번만 생성합니다.
+func getKeyProvider() KeyProvider {
+	keyProviderOnce.Do(func() {
+		if jwtMode == "jwks" {
+			keyProvider = NewJWKSProvider(jwtJWKSURL, 5*time.Minute)
+			return
+		}
+		keyProvider = HMACKeyProvider{}
 	})
-	if err != nil {
-		return "", fmt.Errorf("Parameter Store에서 JWT 시크릿을 가져오는 데 실패했습니다: %w", err)
-	}
-
-	if paramOutput.Parameter == nil || paramOutput.Parameter.Value == nil {
-		return "", errors.New("JWT 시크릿 파라미터 값이 존재하지 않습니다")
-	}
-
-	// 가져온 시크릿을 전역 변수에 캐싱합니다.
-	jwtSecret = *paramOutput.Parameter.Value
-	return jwtSecret, nil
+	return keyProvider
 }
 
-// VerifyToken은 전달된 JWT 문자열을 검증하고, 유효한 경우 사용자의 고유 ID (sub 클레임)를 반환합니다.
-func VerifyToken(ctx context.Context, tokenString string) (string, error) {
+// VerifyToken은 전달된 JWT 문자열을 검증하고, 유효한 경우 사용자의 고유 ID(sub 클레임)와
+// role 클레임을 반환합니다. role은 internal/authz로 넘겨 권한 검사에 사용합니다.
+func VerifyToken(ctx context.Context, tokenString string) (userID string, role string, err error) {
 	if tokenString == "" {
-		return "", errors.New("인증 토큰이 없습니다")
+		return "", "", errors.New("인증 토큰이 없습니다")
 	}
 
 	// "Bearer " 접두사가 있는 경우 제거합니다.
@@ -100,39 +130,148 @@ func VerifyToken(ctx context.Context, tokenString string) (string, error) {
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 	}
 
-	// Parameter Store에서 JWT 시크릿을 가져옵니다. (내부적으로 캐싱 처리됨)
-	secret, err := getJWTSecret(ctx)
-	if err != nil {
-		log.Printf("JWT 시크릿을 가져오는 중 에러 발생: %v", err)
-		return "", errors.New("토큰 검증 중 내부 서버 오류 발생")
+	// 같은 웜 컨테이너에서 짧은 시간 안에 반복 호출된 토큰이라면, 서명 검증/SSM 조회/
+	// denylist 조회를 전부 건너뛰고 캐시된 결과를 바로 돌려줍니다. TokenHash가 서명까지
+	// 포함한 원문 전체를 해시하므로, 캐시에 적중한다는 것은 바로 이 토큰 문자열이 과거에
+	// 이미 서명/iss·aud/denylist 검증을 모두 통과했다는 뜻입니다 — 캐시 키만 따로 위조할
+	// 방법이 없습니다.
+	tokenHash := TokenHash(tokenString)
+	if cached, ok := tokenCache.get(tokenHash); ok {
+		return cached.userID, cached.role, nil
 	}
 
-	// JWT 파싱 및 검증
+	provider := getKeyProvider()
+
+	// JWT 파싱 및 검증. 키 공급자가 HS256 공유 비밀이든 JWKS 기반 공개키든,
+	// provider.Key가 token.Method와 kid를 보고 알맞은 키를 돌려줍니다.
 	token, err := jwt.ParseWithClaims(tokenString, &claims{}, func(token *jwt.Token) (interface{}, error) {
-		// 서명 알고리즘이 HMAC인지 확인합니다. (Supabase 기본값)
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("예상치 못한 서명 알고리즘: %v", token.Header["alg"])
-		}
-		// 검증에 사용할 시크릿 키를 바이트 슬라이스 형태로 반환합니다.
-		return []byte(secret), nil
+		return provider.Key(ctx, token)
 	}, jwt.WithLeeway(5*time.Second)) // 약간의 시간 오차(skew)를 허용합니다.
 
+	// HS256 모드에서 현재 시크릿으로 검증이 실패했다면, 로테이션 직후 구 시크릿으로
+	// 서명된 토큰일 수 있습니다. 바로 직전 시크릿이 캐시돼 있다면 한 번 더 시도합니다.
+	if err != nil && jwtMode != "jwks" {
+		if prev := previousJWTSecret(); prev != "" {
+			if retryToken, retryErr := jwt.ParseWithClaims(tokenString, &claims{}, func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, fmt.Errorf("예상치 못한 서명 알고리즘: %v", token.Header["alg"])
+				}
+				return []byte(prev), nil
+			}, jwt.WithLeeway(5*time.Second)); retryErr == nil {
+				token, err = retryToken, nil
+			}
+		}
+	}
+
 	if err != nil {
 		log.Printf("토큰 파싱/검증 실패: %v", err)
-		return "", fmt.Errorf("유효하지 않은 토큰: %w", err)
+		return "", "", fmt.Errorf("유효하지 않은 토큰: %w", err)
 	}
 
 	// 토큰이 유효하지 않은 경우 에러를 반환합니다.
 	if !token.Valid {
-		return "", errors.New("유효하지 않은 토큰")
+		return "", "", errors.New("유효하지 않은 토큰")
 	}
 
 	// 토큰의 클레임(payload)을 파싱합니다.
 	claims, ok := token.Claims.(*claims)
 	if !ok || claims.UserUID == "" {
-		return "", errors.New("유효하지 않은 토큰 클레임 또는 사용자 ID 없음")
+		return "", "", errors.New("유효하지 않은 토큰 클레임 또는 사용자 ID 없음")
+	}
+
+	if err := validateIssuerAndAudience(claims); err != nil {
+		return "", "", err
+	}
+
+	// 서명 검증을 통과한 토큰이라도, 로그아웃/강제 탈퇴 등으로 사전에 무효화됐을 수
+	// 있으니 denylist를 확인합니다. denylist가 설정되지 않은 환경에서는 건너뜁니다.
+	if store := getDenylistStore(ctx); store != nil {
+		revoked, err := store.IsRevoked(ctx, tokenHash)
+		if err != nil {
+			log.Printf("denylist 조회 실패: %v", err)
+			return "", "", errors.New("토큰 검증 중 내부 서버 오류 발생")
+		}
+		if revoked {
+			return "", "", errors.New("무효화된 토큰입니다")
+		}
+	}
+
+	var exp time.Time
+	if claims.ExpiresAt != nil {
+		exp = claims.ExpiresAt.Time
+	} else {
+		exp = time.Now().Add(positiveTTL)
+	}
+	tokenCache.put(tokenHash, verifiedToken{userID: claims.UserUID, role: claims.UserRole, exp: exp})
+
+	// 클레임에서 사용자 ID와 role을 추출하여 반환합니다.
+	return claims.UserUID, claims.UserRole, nil
+}
+
+// ExpiresAt은 서명을 검증하지 않고 tokenString의 exp 클레임만 읽어옵니다. 이미
+// VerifyToken으로 검증을 마친 토큰을 revoke할 때, 원본 만료 시각을 denylist TTL로
+// 재사용하기 위한 용도이므로 단독으로 신뢰에 사용해서는 안 됩니다.
+func ExpiresAt(tokenString string) (time.Time, error) {
+	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, &claims{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("토큰 파싱 실패: %w", err)
+	}
+	c, ok := parsed.Claims.(*claims)
+	if !ok || c.ExpiresAt == nil {
+		return time.Time{}, errors.New("exp 클레임이 없습니다")
+	}
+	return c.ExpiresAt.Time, nil
+}
+
+// Revoke는 tokenString을 검증한 뒤 denylist에 기록해 즉시 무효화합니다. cmd/revoke가
+// "로그아웃" API의 실체로 사용합니다. denylist가 설정되지 않은 환경에서는 에러를
+// 반환합니다(로그아웃을 조용히 무시하면 탈취된 토큰을 막을 방법이 없어지기 때문입니다).
+func Revoke(ctx context.Context, tokenString string) error {
+	userID, _, err := VerifyToken(ctx, tokenString)
+	if err != nil {
+		return err
+	}
+
+	exp, err := ExpiresAt(tokenString)
+	if err != nil {
+		return err
+	}
+
+	store := getDenylistStore(ctx)
+	if store == nil {
+		return errors.New("denylist가 설정되지 않아 로그아웃을 처리할 수 없습니다")
+	}
+
+	hash := TokenHash(tokenString)
+	if err := store.Revoke(ctx, hash, exp); err != nil {
+		return err
+	}
+
+	// 같은 웜 컨테이너가 남은 positive cache TTL 동안 방금 무효화한 토큰을 계속
+	// 유효하다고 답하지 않도록 캐시에서도 즉시 제거합니다.
+	tokenCache.delete(hash)
+
+	log.Printf("토큰이 무효화되었습니다. 사용자 ID: %s", userID)
+	return nil
+}
+
+// validateIssuerAndAudience는 JWT_ISSUER / JWT_AUDIENCE 환경 변수로 설정된 허용 목록에
+// 대해 토큰의 iss/aud 클레임을 검사합니다. 허용 목록이 비어 있으면 해당 검증은 건너뜁니다.
+func validateIssuerAndAudience(c *claims) error {
+	if len(jwtIssuerAllowList) > 0 && !slices.Contains(jwtIssuerAllowList, c.Issuer) {
+		return fmt.Errorf("허용되지 않은 발급자(iss)입니다: %s", c.Issuer)
+	}
+
+	if len(jwtAudienceAllowList) > 0 {
+		for _, aud := range c.Audience {
+			if slices.Contains(jwtAudienceAllowList, aud) {
+				return nil
+			}
+		}
+		return fmt.Errorf("허용되지 않은 대상(aud)입니다: %v", c.Audience)
 	}
 
-	// 클레임에서 사용자 ID를 추출하여 반환합니다.
-	return claims.UserUID, nil
+	return nil
 }
@@ -0,0 +1,149 @@
+package authz
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// S3Adapter는 S3에 저장된 CSV 정책 파일(casbin 표준 포맷, 예: "p, admin, /tarot/*, GET")을
+// 읽어오는 읽기 전용 persist.Adapter 구현체입니다. 운영자가 파일만 교체하면 다음 cold start
+// 또는 Enforcer.ReloadPolicy 호출 시 새 정책이 반영됩니다.
+type S3Adapter struct {
+	client *s3.Client
+	bucket string
+	key    string
+}
+
+// NewS3Adapter는 bucket/key에 있는 정책 CSV를 읽는 S3Adapter를 생성합니다.
+func NewS3Adapter(client *s3.Client, bucket, key string) *S3Adapter {
+	return &S3Adapter{client: client, bucket: bucket, key: key}
+}
+
+// LoadPolicy는 S3 오브젝트를 내려받아 casbin 모델에 정책 규칙을 채워 넣습니다.
+func (a *S3Adapter) LoadPolicy(m model.Model) error {
+	out, err := a.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(a.key),
+	})
+	if err != nil {
+		return fmt.Errorf("S3에서 정책 파일을 가져오는 데 실패했습니다: %w", err)
+	}
+	defer out.Body.Close()
+
+	reader := csv.NewReader(out.Body)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("정책 CSV 파싱 실패: %w", err)
+	}
+
+	for _, record := range records {
+		persist.LoadPolicyArray(record, m)
+	}
+	return nil
+}
+
+// SavePolicy, AddPolicy, RemovePolicy, RemoveFilteredPolicy는 쓰기를 지원하지 않습니다.
+// 정책 변경은 운영자가 S3 오브젝트를 직접 교체하는 방식으로만 이뤄집니다.
+func (a *S3Adapter) SavePolicy(model.Model) error { return fmt.Errorf("S3Adapter는 읽기 전용입니다") }
+func (a *S3Adapter) AddPolicy(string, string, []string) error {
+	return fmt.Errorf("S3Adapter는 읽기 전용입니다")
+}
+func (a *S3Adapter) RemovePolicy(string, string, []string) error {
+	return fmt.Errorf("S3Adapter는 읽기 전용입니다")
+}
+func (a *S3Adapter) RemoveFilteredPolicy(string, string, int, ...string) error {
+	return fmt.Errorf("S3Adapter는 읽기 전용입니다")
+}
+
+// DynamoDBAdapter는 DynamoDB 테이블에 저장된 정책 행({ptype, sub, obj, act})을 읽어오는
+// persist.Adapter 구현체입니다. S3Adapter보다 변경 빈도가 잦은 환경(운영 콘솔에서 직접 행을
+// 추가/삭제)에 적합합니다.
+type DynamoDBAdapter struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBAdapter는 tableName 테이블에서 정책 행을 읽는 DynamoDBAdapter를 생성합니다.
+func NewDynamoDBAdapter(client *dynamodb.Client, tableName string) *DynamoDBAdapter {
+	return &DynamoDBAdapter{client: client, tableName: tableName}
+}
+
+type dynamoPolicyRow struct {
+	PType string `dynamodbav:"ptype"`
+	Sub   string `dynamodbav:"sub"`
+	Obj   string `dynamodbav:"obj"`
+	Act   string `dynamodbav:"act"`
+}
+
+// LoadPolicy는 테이블을 전체 스캔하여 casbin 모델에 정책 규칙을 채워 넣습니다.
+func (a *DynamoDBAdapter) LoadPolicy(m model.Model) error {
+	ctx := context.Background()
+	var lastKey map[string]types.AttributeValue
+
+	for {
+		out, err := a.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(a.tableName),
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return fmt.Errorf("DynamoDB에서 정책을 스캔하는 데 실패했습니다: %w", err)
+		}
+
+		for _, item := range out.Items {
+			row := dynamoPolicyRowFromItem(item)
+			if row.PType == "" {
+				continue
+			}
+			line := strings.Join([]string{row.PType, row.Sub, row.Obj, row.Act}, ", ")
+			persist.LoadPolicyLine(line, m)
+		}
+
+		if out.LastEvaluatedKey == nil || len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastKey = out.LastEvaluatedKey
+	}
+	return nil
+}
+
+func dynamoPolicyRowFromItem(item map[string]types.AttributeValue) dynamoPolicyRow {
+	get := func(k string) string {
+		if v, ok := item[k].(*types.AttributeValueMemberS); ok {
+			return v.Value
+		}
+		return ""
+	}
+	return dynamoPolicyRow{
+		PType: get("ptype"),
+		Sub:   get("sub"),
+		Obj:   get("obj"),
+		Act:   get("act"),
+	}
+}
+
+// SavePolicy, AddPolicy, RemovePolicy, RemoveFilteredPolicy는 쓰기를 지원하지 않습니다.
+// 정책 변경은 운영 콘솔/스크립트에서 DynamoDB 테이블을 직접 갱신하는 방식으로만 이뤄집니다.
+func (a *DynamoDBAdapter) SavePolicy(model.Model) error {
+	return fmt.Errorf("DynamoDBAdapter는 읽기 전용입니다")
+}
+func (a *DynamoDBAdapter) AddPolicy(string, string, []string) error {
+	return fmt.Errorf("DynamoDBAdapter는 읽기 전용입니다")
+}
+func (a *DynamoDBAdapter) RemovePolicy(string, string, []string) error {
+	return fmt.Errorf("DynamoDBAdapter는 읽기 전용입니다")
+}
+func (a *DynamoDBAdapter) RemoveFilteredPolicy(string, string, int, ...string) error {
+	return fmt.Errorf("DynamoDBAdapter는 읽기 전용입니다")
+}
@@ -0,0 +1,71 @@
+// Package authz는 Casbin 기반 RBAC/ABAC 인가 계층을 제공합니다. authorizer Lambda는
+// internal/auth.VerifyToken으로 신원을 확인한 뒤, 이 패키지의 Enforcer로 "이 role이
+// 이 HTTP 메서드/경로를 호출할 수 있는가"를 한 번 더 검사합니다.
+package authz
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// Enforcer는 casbin.Enforcer를 감싸, cold start 시 모델과 정책을 한 번만 로드하고
+// 이후 요청마다 Enforce 호출만 반복하도록 합니다.
+type Enforcer struct {
+	mu sync.RWMutex
+	e  *casbin.Enforcer
+}
+
+// NewEnforcer는 modelPath(RBAC/keyMatch2 모델 정의 파일)와 adapter(정책을 읽어올 저장소)로
+// Enforcer를 초기화합니다. adapter에는 S3Adapter, DynamoDBAdapter 등을 넘겨, 재배포 없이
+// 정책을 바꿀 수 있게 합니다.
+func NewEnforcer(modelPath string, adapter persist.Adapter) (*Enforcer, error) {
+	e, err := casbin.NewEnforcer(modelPath, adapter)
+	if err != nil {
+		return nil, fmt.Errorf("casbin enforcer 초기화 실패: %w", err)
+	}
+	return &Enforcer{e: e}, nil
+}
+
+// Enforce는 role(없으면 userID)이 httpMethod로 resourcePath를 호출할 권한이 있는지
+// 검사합니다. role 기반 정책이 우선이며, 매치되지 않으면 userID를 주체로 한 번 더
+// 검사해 사용자 단위 예외 정책도 지원합니다. 반환하는 matchedSubject는 실제로 권한을
+// 내준 주체("role" 또는 "userID" 중 하나)이며, 거부됐다면 빈 문자열입니다. 호출부가
+// 어떤 주체로 통과했는지 알아야 하는 후속 검사/로그에 사용합니다.
+func (en *Enforcer) Enforce(userID, role, httpMethod, resourcePath string) (allowed bool, matchedSubject string, err error) {
+	en.mu.RLock()
+	defer en.mu.RUnlock()
+
+	if role != "" {
+		ok, err := en.e.Enforce(role, resourcePath, httpMethod)
+		if err != nil {
+			return false, "", fmt.Errorf("casbin enforce 실패(role=%s): %w", role, err)
+		}
+		if ok {
+			return true, role, nil
+		}
+	}
+
+	ok, err := en.e.Enforce(userID, resourcePath, httpMethod)
+	if err != nil {
+		return false, "", fmt.Errorf("casbin enforce 실패(userID=%s): %w", userID, err)
+	}
+	if ok {
+		return true, userID, nil
+	}
+	return false, "", nil
+}
+
+// ReloadPolicy는 운영자가 어댑터(S3 오브젝트/DynamoDB 테이블)에 반영한 정책 변경 사항을
+// 다시 읽어옵니다. Lambda 웜 컨테이너가 재시작 없이 최신 정책을 반영하고 싶을 때 호출합니다.
+func (en *Enforcer) ReloadPolicy() error {
+	en.mu.Lock()
+	defer en.mu.Unlock()
+
+	if err := en.e.LoadPolicy(); err != nil {
+		return fmt.Errorf("정책 재로드 실패: %w", err)
+	}
+	return nil
+}
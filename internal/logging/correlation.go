@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// CorrelationIDHeader는 요청을 authorizer부터 후속 Lambda까지 하나의 ID로 꿰기 위해
+// 쓰는 컨텍스트 키입니다. authorizer의 context 맵과 후속 Lambda의 Authorizer 맵 양쪽에
+// 같은 키로 저장/조회합니다.
+const CorrelationIDHeader = "correlation_id"
+
+// CorrelationID는 API Gateway가 X-Ray와 연동해 붙여주는 X-Amzn-Trace-Id 헤더를
+// correlation ID로 재사용합니다. 헤더가 없으면(X-Ray 비활성 등) 새 UUID를 생성합니다.
+func CorrelationID(headers map[string]string) string {
+	if traceID, ok := headers["X-Amzn-Trace-Id"]; ok && traceID != "" {
+		return traceID
+	}
+	if traceID, ok := headers["x-amzn-trace-id"]; ok && traceID != "" {
+		return traceID
+	}
+	return newUUID()
+}
+
+// newUUID는 RFC 4122 version 4 UUID를 생성합니다. 외부 의존성 없이 crypto/rand만으로
+// 충분하므로 별도 패키지를 추가하지 않았습니다.
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand 실패는 사실상 불가능하지만, 상관 ID 없이 로그가 끊기지 않도록
+		// 고정 플레이스홀더를 반환합니다.
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
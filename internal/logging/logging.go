@@ -0,0 +1,17 @@
+// Package logging은 모든 Lambda가 공유하는 구조화 로깅 설정을 제공합니다. log.Printf로
+// 찍던 비정형 한/영 혼용 문자열 대신 log/slog의 JSON 핸들러를 사용해, CloudWatch Logs
+// Insights에서 `filter decision="Deny" | stats count() by reason` 같은 쿼리가 바로
+// 동작하게 합니다.
+package logging
+
+import (
+	"log/slog"
+	"os"
+)
+
+// New은 표준 출력에 JSON으로 기록하는 *slog.Logger를 생성합니다. Lambda 런타임은 표준
+// 출력을 그대로 CloudWatch Logs로 보내므로 별도 핸들러 배선이 필요 없습니다.
+func New() *slog.Logger {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
+	return slog.New(handler)
+}
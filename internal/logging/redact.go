@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// sensitiveHeaders는 로그에 원문이 남으면 안 되는 헤더 이름입니다(소문자로 비교).
+var sensitiveHeaders = map[string]bool{
+	"authorization": true,
+}
+
+// Redact는 토큰처럼 민감한 문자열을 그대로 로그에 남기지 않도록 "<redacted:sha256:...>"
+// 형태로 바꿔줍니다. 해시는 남겨, 같은 토큰이 여러 로그 라인에 등장하는지 정도는
+// 추적할 수 있게 합니다.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	sum := sha256.Sum256([]byte(s))
+	return "<redacted:sha256:" + hex.EncodeToString(sum[:]) + ">"
+}
+
+// RedactHeaders는 headers를 복사하면서 Authorization 헤더(대소문자 무관)만 Redact로
+// 치환합니다. 원본 맵은 건드리지 않습니다.
+func RedactHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if sensitiveHeaders[strings.ToLower(k)] {
+			redacted[k] = Redact(v)
+			continue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
@@ -0,0 +1,186 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"tarot-backend/internal/auth"
+)
+
+// ErrReuseDetected는 이미 사용 처리된 refresh token이 다시 제시됐을 때 반환됩니다.
+// 토큰 탈취 신호이므로, Refresh는 이 에러를 돌려주기 전에 제시된 토큰과 같은 family의
+// refresh token을 모두 만료시킵니다. 폐기 중 오류가 나면 그 오류를 덧붙여 감싼
+// ErrReuseDetected를 반환합니다. 다른 family와 이미 발급된 access token은 건드리지 않습니다.
+var ErrReuseDetected = errors.New("refresh token 재사용이 감지되었습니다")
+
+// ErrInvalidRefreshToken은 존재하지 않거나 만료된 refresh token에 대해 반환됩니다.
+var ErrInvalidRefreshToken = errors.New("유효하지 않거나 만료된 refresh token입니다")
+
+// Service는 refresh token 발급과 로테이션을 담당합니다.
+type Service struct {
+	store      *store
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewService는 tableName 테이블을 사용하는 Service를 생성합니다. accessTTL은 새로
+// 발급하는 access token의 수명, refreshTTL은 새로 발급하는 refresh token의 수명입니다.
+func NewService(client *dynamodb.Client, tableName string, accessTTL, refreshTTL time.Duration) *Service {
+	return &Service{
+		store:      newStore(client, tableName),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// IssuedPair는 로그인/로테이션 한 번의 결과로 돌려주는 access/refresh token 쌍입니다.
+type IssuedPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// IssueInitial은 새 refresh token family를 시작합니다. userID/role이 확인된 로그인
+// 직후(혹은 Supabase 세션 교환 직후) 호출합니다.
+func (s *Service) IssueInitial(ctx context.Context, userID, role string) (IssuedPair, error) {
+	familyID, err := randomToken()
+	if err != nil {
+		return IssuedPair{}, fmt.Errorf("family_id 생성 실패: %w", err)
+	}
+	return s.issuePair(ctx, userID, role, familyID)
+}
+
+// Refresh는 RFC 상 "refresh token rotation" 패턴을 구현합니다: presented가 아직
+// 사용되지 않았고 만료되지 않았으면, 사용 처리(atomic) 후 새 access/refresh token 쌍을
+// 발급합니다. 이미 사용 처리된 토큰이 다시 들어오면 전체 family를 폐기하고
+// ErrReuseDetected를 반환합니다 — 탈취된 토큰이 재사용된 것으로 간주하는 보안 불변식입니다.
+func (s *Service) Refresh(ctx context.Context, presented string) (IssuedPair, error) {
+	hash := hashRefreshToken(presented)
+
+	rec, err := s.store.get(ctx, hash)
+	if err != nil {
+		return IssuedPair{}, err
+	}
+	if rec == nil || time.Now().After(time.Unix(rec.ExpiresAt, 0)) {
+		return IssuedPair{}, ErrInvalidRefreshToken
+	}
+	if rec.UsedAt != nil {
+		if revokeErr := s.store.revokeFamily(ctx, rec.FamilyID); revokeErr != nil {
+			return IssuedPair{}, fmt.Errorf("%w (family 폐기 중 추가 오류: %v)", ErrReuseDetected, revokeErr)
+		}
+		return IssuedPair{}, ErrReuseDetected
+	}
+
+	newRefreshPlain, err := randomToken()
+	if err != nil {
+		return IssuedPair{}, fmt.Errorf("refresh token 생성 실패: %w", err)
+	}
+	newHash := hashRefreshToken(newRefreshPlain)
+
+	conditionFailed, err := s.store.markUsed(ctx, hash, newHash)
+	if err != nil {
+		// 스로틀링/네트워크 오류 등 조건부 쓰기 자체가 확인되지 않은 실패는 reuse가
+		// 아니라 단순 재시도 가능한 오류이므로, family를 폐기하지 않고 그대로 올립니다.
+		return IssuedPair{}, fmt.Errorf("refresh token 사용 처리 실패: %w", err)
+	}
+	if conditionFailed {
+		// 조건부 쓰기가 ConditionalCheckFailedException으로 실패했다면 동시에 들어온
+		// 또 다른 요청이 먼저 사용 처리를 마친 것이므로, 이를 reuse로 취급해 family를
+		// 폐기합니다.
+		if revokeErr := s.store.revokeFamily(ctx, rec.FamilyID); revokeErr != nil {
+			return IssuedPair{}, fmt.Errorf("%w (family 폐기 중 추가 오류: %v)", ErrReuseDetected, revokeErr)
+		}
+		return IssuedPair{}, ErrReuseDetected
+	}
+
+	access, err := auth.IssueAccessToken(ctx, rec.UserID, rec.Role, s.accessTTL)
+	if err != nil {
+		return IssuedPair{}, err
+	}
+
+	now := time.Now()
+	if err := s.store.put(ctx, record{
+		TokenHash: newHash,
+		UserID:    rec.UserID,
+		Role:      rec.Role,
+		FamilyID:  rec.FamilyID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.refreshTTL).Unix(),
+	}); err != nil {
+		return IssuedPair{}, err
+	}
+
+	return IssuedPair{AccessToken: access, RefreshToken: newRefreshPlain}, nil
+}
+
+// IntrospectionResult는 RFC 7662(OAuth 2.0 Token Introspection)의 응답 필드 중,
+// authorizer가 opaque 토큰을 확인하는 데 필요한 최소 집합입니다.
+type IntrospectionResult struct {
+	Active bool   `json:"active"`
+	Sub    string `json:"sub,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Exp    int64  `json:"exp,omitempty"`
+}
+
+// Introspect는 opaque refresh token의 현재 상태를 RFC 7662 스타일로 보고합니다. 사용
+// 처리됐거나 만료된 토큰은 active=false로 보고합니다.
+func (s *Service) Introspect(ctx context.Context, presented string) (IntrospectionResult, error) {
+	rec, err := s.store.get(ctx, hashRefreshToken(presented))
+	if err != nil {
+		return IntrospectionResult{}, err
+	}
+	if rec == nil || rec.UsedAt != nil || time.Now().After(time.Unix(rec.ExpiresAt, 0)) {
+		return IntrospectionResult{Active: false}, nil
+	}
+	return IntrospectionResult{Active: true, Sub: rec.UserID, Role: rec.Role, Exp: rec.ExpiresAt}, nil
+}
+
+func (s *Service) issuePair(ctx context.Context, userID, role, familyID string) (IssuedPair, error) {
+	access, err := auth.IssueAccessToken(ctx, userID, role, s.accessTTL)
+	if err != nil {
+		return IssuedPair{}, err
+	}
+
+	refreshPlain, err := randomToken()
+	if err != nil {
+		return IssuedPair{}, fmt.Errorf("refresh token 생성 실패: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.store.put(ctx, record{
+		TokenHash: hashRefreshToken(refreshPlain),
+		UserID:    userID,
+		Role:      role,
+		FamilyID:  familyID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.refreshTTL).Unix(),
+	}); err != nil {
+		return IssuedPair{}, err
+	}
+
+	return IssuedPair{AccessToken: access, RefreshToken: refreshPlain}, nil
+}
+
+// randomToken은 256비트 암호학적 난수를 base64url로 인코딩한 불투명 refresh token
+// 문자열을 생성합니다.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken은 DB에 평문 refresh token을 저장하지 않기 위해 조회 키로 사용할
+// SHA-256 해시를 계산합니다.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+// Package tokens는 refresh-token 발급/로테이션을 담당합니다. access token 자체는
+// internal/auth가 발급/검증하며, 이 패키지는 "다음 access token을 받을 자격이 있는가"를
+// DynamoDB에 저장된 refresh token 계보(family)로 추적합니다.
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// record는 refresh token 한 건에 대해 테이블에 저장하는 행입니다. 같은 family_id를
+// 공유하는 행들은 하나의 로그인 세션에서 파생된 refresh token 계보를 이룹니다.
+type record struct {
+	TokenHash  string `dynamodbav:"token_hash"`
+	UserID     string `dynamodbav:"user_id"`
+	Role       string `dynamodbav:"role"`
+	FamilyID   string `dynamodbav:"family_id"`
+	IssuedAt   int64  `dynamodbav:"issued_at"`
+	ExpiresAt  int64  `dynamodbav:"expires_at"`
+	UsedAt     *int64 `dynamodbav:"used_at,omitempty"`
+	ReplacedBy string `dynamodbav:"replaced_by,omitempty"`
+}
+
+// store는 refresh token 행을 읽고 쓰는 DynamoDB 기반 저장소입니다. family_id로 전체
+// 계보를 찾기 위해 테이블에 "family_id-index"라는 GSI(PK: family_id)가 있어야 합니다.
+type store struct {
+	client        *dynamodb.Client
+	tableName     string
+	familyIDIndex string
+}
+
+func newStore(client *dynamodb.Client, tableName string) *store {
+	return &store{client: client, tableName: tableName, familyIDIndex: "family_id-index"}
+}
+
+func (s *store) get(ctx context.Context, tokenHash string) (*record, error) {
+	key, err := attributevalue.MarshalMap(map[string]string{"token_hash": tokenHash})
+	if err != nil {
+		return nil, fmt.Errorf("조회 키 마샬링 실패: %w", err)
+	}
+
+	out, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("refresh token 조회 실패: %w", err)
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var rec record
+	if err := attributevalue.UnmarshalMap(out.Item, &rec); err != nil {
+		return nil, fmt.Errorf("refresh token 언마샬링 실패: %w", err)
+	}
+	return &rec, nil
+}
+
+func (s *store) put(ctx context.Context, rec record) error {
+	item, err := attributevalue.MarshalMap(rec)
+	if err != nil {
+		return fmt.Errorf("refresh token 마샬링 실패: %w", err)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("refresh token 저장 실패: %w", err)
+	}
+	return nil
+}
+
+// markUsed는 tokenHash 행에 used_at/replaced_by를 기록합니다. used_at이 이미 설정돼
+// 있으면 조건부 쓰기가 ConditionalCheckFailedException으로 실패하는데, 이는 동시에 들어온
+// 두 번째 재사용 시도이므로 호출부가 이를 reuse 신호로 취급해야 합니다. 반환값
+// conditionFailed가 그 신호이고, 스로틀링/네트워크 오류 등 그 밖의 실패는 err로만
+// 전달되므로 호출부가 이를 reuse와 혼동해 family 전체를 폐기하지 않도록 해야 합니다.
+func (s *store) markUsed(ctx context.Context, tokenHash, replacedBy string) (conditionFailed bool, err error) {
+	update := expression.Set(expression.Name("used_at"), expression.Value(time.Now().Unix())).
+		Set(expression.Name("replaced_by"), expression.Value(replacedBy))
+	condition := expression.AttributeNotExists(expression.Name("used_at"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return false, fmt.Errorf("update expression 빌드 실패: %w", err)
+	}
+
+	key, err := attributevalue.MarshalMap(map[string]string{"token_hash": tokenHash})
+	if err != nil {
+		return false, fmt.Errorf("update 키 마샬링 실패: %w", err)
+	}
+
+	_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.tableName),
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return true, nil
+		}
+		return false, fmt.Errorf("refresh token 사용 처리 실패: %w", err)
+	}
+	return false, nil
+}
+
+// revokeFamily는 family_id-index GSI로 계보 전체를 조회한 뒤, 모든 행의 만료 시각을
+// 지금으로 당겨 더 이상 로테이션에 쓰일 수 없게 만듭니다. 탈취된 refresh token이
+// 재사용된 것을 감지했을 때 호출합니다.
+func (s *store) revokeFamily(ctx context.Context, familyID string) error {
+	keyCond := expression.Key("family_id").Equal(expression.Value(familyID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return fmt.Errorf("query expression 빌드 실패: %w", err)
+	}
+
+	out, err := s.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(s.tableName),
+		IndexName:                 aws.String(s.familyIDIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return fmt.Errorf("family_id로 refresh token 조회 실패: %w", err)
+	}
+
+	now := time.Now().Unix()
+	for _, item := range out.Items {
+		var rec record
+		if err := attributevalue.UnmarshalMap(item, &rec); err != nil {
+			continue
+		}
+		rec.ExpiresAt = now
+		if err := s.put(ctx, rec); err != nil {
+			return fmt.Errorf("family 전체 폐기 중 실패(token_hash=%s): %w", rec.TokenHash, err)
+		}
+	}
+	return nil
+}